@@ -0,0 +1,20 @@
+package main
+
+import "strings"
+
+// flakyMarkSentinel is the line flakymark.Mark logs; kept in sync with
+// flakymark.SentinelPrefix without importing the flakymark package itself,
+// since that package depends on "testing" and has no business being linked
+// into this binary.
+const flakyMarkSentinel = "flakytest: this is a known flaky test: "
+
+// detectFlakyMark scans a test's captured output for the flakymark
+// sentinel line and, if present, returns the issue URL that followed it.
+func detectFlakyMark(output []string) (marked bool, issueURL string) {
+	for _, line := range output {
+		if idx := strings.Index(line, flakyMarkSentinel); idx != -1 {
+			return true, strings.TrimSpace(line[idx+len(flakyMarkSentinel):])
+		}
+	}
+	return false, ""
+}
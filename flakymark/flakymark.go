@@ -0,0 +1,22 @@
+// Package flakymark lets a test announce itself as a known flaky test, so
+// flaky-test-tracker's analyze and rerun commands can tell a marked,
+// expected flake apart from one nobody has triaged yet.
+package flakymark
+
+import "testing"
+
+// SentinelPrefix is the line flaky-test-tracker's parsers look for in a
+// test's log output to recognize it as marked flaky.
+const SentinelPrefix = "flakytest: this is a known flaky test: "
+
+// Mark records t as a known flaky test tracked at issueURL. Call it at the
+// top of the test body (or subtest body):
+//
+//	func TestFlaky(t *testing.T) {
+//	    flakymark.Mark(t, "https://github.com/org/repo/issues/123")
+//	    ...
+//	}
+func Mark(t *testing.T, issueURL string) {
+	t.Helper()
+	t.Log(SentinelPrefix + issueURL)
+}
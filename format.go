@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// printFlakyTestsText renders flakyTests as the table analyze has always
+// printed to stdout.
+func printFlakyTestsText(flakyTests []FlakyTest, threshold float64) {
+	fmt.Println("\n--- Flaky Test Report ---")
+	if len(flakyTests) == 0 {
+		fmt.Printf("No tests identified as flaky (threshold: %.2f).\n", threshold)
+		return
+	}
+	fmt.Printf("Identified %d potentially flaky tests (threshold: %.2f):\n", len(flakyTests), threshold)
+	fmt.Printf("%-50s %-20s %-15s %-10s %-10s\n", "TEST NAME", "PACKAGE", "FLAKINESS SCORE", "FAILURES", "TOTAL RUNS")
+	fmt.Println(strings.Repeat("-", 105))
+	for _, ft := range flakyTests {
+		fmt.Printf("%-50s %-20s %-15.2f%% %-10d %-10d\n",
+			ft.Name, ft.Package, ft.FlakinessScore*100, ft.Failures, ft.TotalRuns)
+	}
+}
+
+// printFlakyTestsJSON renders flakyTests as a JSON array.
+func printFlakyTestsJSON(flakyTests []FlakyTest) error {
+	data, err := json.MarshalIndent(flakyTests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling flaky tests to JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// junitTestSuite is the subset of the JUnit XML schema analyze needs:
+// flaky tests are reported as passing testcases with a <flakyFailure>
+// child, matching the convention Jenkins/Surefire use so existing CI
+// dashboards that already parse JUnit pick them up without a build failure.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName    string             `xml:"classname,attr"`
+	Name         string             `xml:"name,attr"`
+	FlakyFailure *junitFlakyFailure `xml:"flakyFailure,omitempty"`
+}
+
+type junitFlakyFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// printFlakyTestsJUnit renders flakyTests as a JUnit XML report. A test
+// already marked flaky (flakymark.Mark, with a tracking issue) gets a
+// distinct message so CI dashboards can tell "known, already triaged" apart
+// from "newly flaky, needs triage" at a glance instead of treating both as
+// identical noise.
+func printFlakyTestsJUnit(flakyTests []FlakyTest) error {
+	suite := junitTestSuite{
+		Name:  "flaky-tests",
+		Tests: len(flakyTests),
+	}
+	for _, ft := range flakyTests {
+		var message string
+		if ft.IsMarkedFlaky {
+			message = fmt.Sprintf("known flaky (%s): %.2f%% flakiness score (%d/%d runs failed)", ft.IssueURL, ft.FlakinessScore*100, ft.Failures, ft.TotalRuns)
+		} else {
+			message = fmt.Sprintf("unmarked flaky: %.2f%% flakiness score (%d/%d runs failed)", ft.FlakinessScore*100, ft.Failures, ft.TotalRuns)
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			ClassName: ft.Package,
+			Name:      ft.Name,
+			FlakyFailure: &junitFlakyFailure{
+				Message: message,
+				Text:    fmt.Sprintf("Failed %d of %d runs; %d pass/fail transitions observed.", ft.Failures, ft.TotalRuns, ft.TransitionCount),
+			},
+		})
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling flaky tests to JUnit XML: %w", err)
+	}
+	fmt.Println(xml.Header + string(data))
+	return nil
+}
+
+// printFlakyTestsGithub renders flakyTests as GitHub Actions "::warning::"
+// workflow commands, so flakes surface as annotations on the job's Checks
+// tab without failing the build. FlakyTest carries no file/line (the parsers
+// only ever see a package and test name), so these can't be anchored to a
+// line in the PR diff the way a `file=...,line=...` annotation would be;
+// they show up as job-level warnings, not inline diff comments.
+//
+// A test already marked flaky (flakymark.Mark, with a tracking issue) is
+// already-triaged, expected noise, so it gets a distinct "Known flaky" title
+// instead of the plain "Unmarked flaky test" title unmarked tests get -
+// otherwise the annotation gives no signal beyond what the marker itself
+// already recorded.
+func printFlakyTestsGithub(flakyTests []FlakyTest) {
+	for _, ft := range flakyTests {
+		if ft.IsMarkedFlaky {
+			fmt.Printf("::warning title=Known flaky (see issue)::%s.%s is flaky (%.2f%% flakiness score, %d/%d runs failed); tracked at %s\n",
+				ft.Package, ft.Name, ft.FlakinessScore*100, ft.Failures, ft.TotalRuns, ft.IssueURL)
+			continue
+		}
+		fmt.Printf("::warning title=Unmarked flaky test::%s.%s is flaky (%.2f%% flakiness score, %d/%d runs failed)\n",
+			ft.Package, ft.Name, ft.FlakinessScore*100, ft.Failures, ft.TotalRuns)
+	}
+}
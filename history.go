@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// RunMetadata captures the provenance of a batch of TestResults so
+// longitudinal queries can slice history by commit, branch, or CI job.
+type RunMetadata struct {
+	GitCommit string
+	Branch    string
+	CIJob     string
+}
+
+// HistoryFilter narrows a HistoryStore.Query call.
+type HistoryFilter struct {
+	Since     time.Time // zero value means "no lower bound"
+	GitCommit string    // exact match; empty means "any commit"
+}
+
+// HistoryStore persists TestResults across invocations so flakiness can be
+// computed over weeks of history rather than a single log file.
+type HistoryStore interface {
+	// Record appends a TestResult, tagged with the metadata of the run it
+	// came from.
+	Record(ctx context.Context, result TestResult, meta RunMetadata) error
+	// Query returns every persisted result matching filter, across all
+	// packages and tests.
+	Query(ctx context.Context, filter HistoryFilter) ([]TestResult, error)
+	Close() error
+}
+
+// SQLiteStore is the default HistoryStore, backed by modernc.org/sqlite so
+// the binary stays CGO-free.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database at path
+// and ensures its schema is up to date.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sqlite database %s: %w", path, err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS test_results (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	package         TEXT NOT NULL,
+	name            TEXT NOT NULL,
+	status          TEXT NOT NULL,
+	duration        REAL NOT NULL,
+	timestamp       DATETIME NOT NULL,
+	git_commit      TEXT NOT NULL DEFAULT '',
+	branch          TEXT NOT NULL DEFAULT '',
+	ci_job          TEXT NOT NULL DEFAULT '',
+	output          TEXT NOT NULL DEFAULT '',
+	is_marked_flaky INTEGER NOT NULL DEFAULT 0,
+	issue_url       TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_test_results_pkg_name ON test_results(package, name);
+CREATE INDEX IF NOT EXISTS idx_test_results_timestamp ON test_results(timestamp);
+CREATE INDEX IF NOT EXISTS idx_test_results_git_commit ON test_results(git_commit);
+`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error migrating sqlite schema: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Record(ctx context.Context, result TestResult, meta RunMetadata) error {
+	_, err := s.db.ExecContext(ctx, `
+INSERT INTO test_results (package, name, status, duration, timestamp, git_commit, branch, ci_job, output, is_marked_flaky, issue_url)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		result.Package, result.Name, result.Status, result.Duration, result.Timestamp,
+		meta.GitCommit, meta.Branch, meta.CIJob,
+		strings.Join(result.Output, "\n"), result.IsMarkedFlaky, result.IssueURL)
+	if err != nil {
+		return fmt.Errorf("error recording test result for %s/%s: %w", result.Package, result.Name, err)
+	}
+	return nil
+}
+
+// Query returns persisted results matching filter. Since/GitCommit are both
+// optional: Since narrows by recency, GitCommit pins to one commit so a
+// caller can compare flakiness across two Query calls at different commits
+// to answer "did this test get flakier since commit X?".
+func (s *SQLiteStore) Query(ctx context.Context, filter HistoryFilter) ([]TestResult, error) {
+	query := `
+SELECT package, name, status, duration, timestamp, output, is_marked_flaky, issue_url
+FROM test_results
+WHERE timestamp >= ?`
+	args := []any{filter.Since}
+
+	if filter.GitCommit != "" {
+		query += " AND git_commit = ?"
+		args = append(args, filter.GitCommit)
+	}
+	query += " ORDER BY package, name, timestamp"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying test history: %w", err)
+	}
+	defer rows.Close()
+
+	var results []TestResult
+	for rows.Next() {
+		var r TestResult
+		var output string
+		if err := rows.Scan(&r.Package, &r.Name, &r.Status, &r.Duration, &r.Timestamp, &output, &r.IsMarkedFlaky, &r.IssueURL); err != nil {
+			return nil, fmt.Errorf("error scanning test history row: %w", err)
+		}
+		if output != "" {
+			r.Output = strings.Split(output, "\n")
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading test history: %w", err)
+	}
+
+	return results, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// detectRunMetadata fills in git commit/branch from the local checkout and
+// CI job name from common CI environment variables. Any piece that can't be
+// determined is left blank rather than failing the ingest.
+func detectRunMetadata() RunMetadata {
+	return RunMetadata{
+		GitCommit: gitOutput("rev-parse", "HEAD"),
+		Branch:    gitOutput("rev-parse", "--abbrev-ref", "HEAD"),
+		CIJob:     firstNonEmptyEnv("GITHUB_JOB", "CI_JOB_NAME", "JOB_NAME"),
+	}
+}
+
+func gitOutput(args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func firstNonEmptyEnv(names ...string) string {
+	for _, name := range names {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
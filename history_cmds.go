@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// openHistoryStore opens the SQLite history store at the --db path shared
+// by the ingest and report commands.
+func openHistoryStore(cmd *cobra.Command) *SQLiteStore {
+	dbPath, _ := cmd.Flags().GetString("db")
+	store, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		log.Fatalf("Error opening history store %s: %v", dbPath, err)
+	}
+	return store
+}
+
+// ingestCmd represents the ingest command
+var ingestCmd = &cobra.Command{
+	Use:   "ingest [file...]",
+	Short: "Append Go test results to the persistent history store",
+	Long: `Parses one or more Go test output files (plain text or 'go test -json')
+and appends every test result to the history database, tagged with the
+current git commit, branch, and CI job if available.
+
+Example:
+  go test -json ./... > test_output.json
+  flaky-test-tracker ingest test_output.json
+`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var readers []*os.File
+		if len(args) == 0 {
+			readers = append(readers, os.Stdin)
+		} else {
+			for _, filePath := range args {
+				file, err := os.Open(filePath)
+				if err != nil {
+					log.Fatalf("Error opening file %s: %v", filePath, err)
+				}
+				defer file.Close()
+				readers = append(readers, file)
+			}
+		}
+
+		store := openHistoryStore(cmd)
+		defer store.Close()
+
+		meta := detectRunMetadata()
+		ctx := context.Background()
+
+		var count int
+		for _, reader := range readers {
+			results, err := parseTestOutput(reader)
+			if err != nil {
+				log.Fatalf("Error parsing test output: %v", err)
+			}
+			for _, r := range results {
+				if err := store.Record(ctx, r, meta); err != nil {
+					log.Fatalf("Error ingesting test result: %v", err)
+				}
+				count++
+			}
+		}
+
+		fmt.Printf("Ingested %d test result(s) into the history store.\n", count)
+	},
+}
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report flaky tests computed over the persistent history store",
+	Long: `Queries the history database built up by 'ingest' and reports
+flaky tests over a time window, rather than a single log file. This
+enables trend queries a single log can't answer, such as "has this test
+gotten flakier over the last week?".
+
+Example:
+  flaky-test-tracker report --since=7d --min-runs=20
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		since, _ := cmd.Flags().GetString("since")
+		minRuns, _ := cmd.Flags().GetInt("min-runs")
+		commit, _ := cmd.Flags().GetString("commit")
+
+		cutoff, err := parseSince(since)
+		if err != nil {
+			log.Fatalf("Error parsing --since %q: %v", since, err)
+		}
+
+		store := openHistoryStore(cmd)
+		defer store.Close()
+
+		results, err := store.Query(context.Background(), HistoryFilter{Since: cutoff, GitCommit: commit})
+		if err != nil {
+			log.Fatalf("Error querying history store: %v", err)
+		}
+		if len(results) == 0 {
+			fmt.Println("No test results found in the requested window.")
+			return
+		}
+
+		flakyTests := calculateFlakiness(results, 0.1)
+
+		var filtered []FlakyTest
+		for _, ft := range flakyTests {
+			if ft.TotalRuns >= minRuns {
+				filtered = append(filtered, ft)
+			}
+		}
+
+		fmt.Printf("\n--- Flaky Test Report (since %s, min-runs %d) ---\n", since, minRuns)
+		if len(filtered) == 0 {
+			fmt.Println("No tests identified as flaky.")
+			return
+		}
+		fmt.Printf("%-50s %-20s %-15s %-10s %-10s\n", "TEST NAME", "PACKAGE", "FLAKINESS SCORE", "FAILURES", "TOTAL RUNS")
+		fmt.Println(strings.Repeat("-", 105))
+		for _, ft := range filtered {
+			fmt.Printf("%-50s %-20s %-15.2f%% %-10d %-10d\n",
+				ft.Name, ft.Package, ft.FlakinessScore*100, ft.Failures, ft.TotalRuns)
+		}
+	},
+}
+
+// parseSince parses a duration like "7d" (days aren't supported by
+// time.ParseDuration) in addition to anything time.ParseDuration accepts,
+// and returns the corresponding cutoff time.
+func parseSince(s string) (time.Time, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid day count: %w", err)
+		}
+		return time.Now().Add(-time.Duration(days) * 24 * time.Hour), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-d), nil
+}
+
+func init() {
+	ingestCmd.Flags().String("db", "flaky_test_history.db", "Path to the SQLite history database")
+	reportCmd.Flags().String("db", "flaky_test_history.db", "Path to the SQLite history database")
+	reportCmd.Flags().String("since", "7d", "Only consider results recorded within this window (e.g. 24h, 7d)")
+	reportCmd.Flags().Int("min-runs", 20, "Only report tests with at least this many recorded runs")
+	reportCmd.Flags().String("commit", "", "Only consider results ingested at this exact git commit (e.g. to compare flakiness across commits); empty means any commit")
+
+	rootCmd.AddCommand(ingestCmd)
+	rootCmd.AddCommand(reportCmd)
+}
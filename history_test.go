@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteStoreRecordAndQuery(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	results := []struct {
+		result TestResult
+		meta   RunMetadata
+	}{
+		{
+			result: TestResult{
+				Package: "pkg", Name: "TestA", Status: "FAIL", Duration: 0.5,
+				Timestamp: now, Output: []string{"line one", "line two"},
+				IsMarkedFlaky: true, IssueURL: "https://issue/1",
+			},
+			meta: RunMetadata{GitCommit: "abc123", Branch: "main", CIJob: "ci"},
+		},
+		{
+			result: TestResult{
+				Package: "pkg", Name: "TestB", Status: "PASS", Duration: 0.1,
+				Timestamp: now.Add(time.Hour),
+			},
+			meta: RunMetadata{GitCommit: "def456", Branch: "main", CIJob: "ci"},
+		},
+	}
+	for _, r := range results {
+		if err := store.Record(ctx, r.result, r.meta); err != nil {
+			t.Fatalf("Record(%s): %v", r.result.Name, err)
+		}
+	}
+
+	all, err := store.Query(ctx, HistoryFilter{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(all), all)
+	}
+
+	byName := make(map[string]TestResult, len(all))
+	for _, r := range all {
+		byName[r.Name] = r
+	}
+
+	a, ok := byName["TestA"]
+	if !ok {
+		t.Fatalf("missing TestA result")
+	}
+	if !a.IsMarkedFlaky || a.IssueURL != "https://issue/1" {
+		t.Errorf("TestA marker = (%v, %q), want (true, https://issue/1)", a.IsMarkedFlaky, a.IssueURL)
+	}
+	if len(a.Output) != 2 || a.Output[0] != "line one" || a.Output[1] != "line two" {
+		t.Errorf("TestA.Output = %q, want the two recorded lines round-tripped", a.Output)
+	}
+
+	b, ok := byName["TestB"]
+	if !ok {
+		t.Fatalf("missing TestB result")
+	}
+	if b.IsMarkedFlaky {
+		t.Errorf("TestB.IsMarkedFlaky = true, want false")
+	}
+
+	filtered, err := store.Query(ctx, HistoryFilter{GitCommit: "abc123"})
+	if err != nil {
+		t.Fatalf("Query with GitCommit filter: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Name != "TestA" {
+		t.Fatalf("GitCommit filter = %+v, want only TestA", filtered)
+	}
+
+	since, err := store.Query(ctx, HistoryFilter{Since: now.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatalf("Query with Since filter: %v", err)
+	}
+	if len(since) != 1 || since[0].Name != "TestB" {
+		t.Fatalf("Since filter = %+v, want only TestB", since)
+	}
+}
@@ -9,10 +9,11 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/sushant-115/flaky-test-detector/scoring"
 )
 
 // TestResult represents the outcome of a single test run.
@@ -22,6 +23,13 @@ type TestResult struct {
 	Duration  float64   // Duration in seconds
 	Timestamp time.Time // When the test result was recorded
 	Package   string    // Go package the test belongs to
+	Output    []string  // Captured log output for this test, if available
+
+	// IsMarkedFlaky and IssueURL are set when Output contains the
+	// flakymark.Mark sentinel line, i.e. the test declared itself a known
+	// flaky test rather than being inferred as one from its history.
+	IsMarkedFlaky bool
+	IssueURL      string
 }
 
 // TestHistory stores all recorded results for a specific test.
@@ -29,13 +37,22 @@ type TestHistory struct {
 	Results []TestResult
 }
 
-// FlakyTest represents a test identified as flaky, with its flakiness score.
+// FlakyTest represents a test identified as flaky, with its composite
+// flakiness score and the individual signals it was built from (see the
+// scoring package) so callers can filter or sort by whichever one matters.
 type FlakyTest struct {
-	Name           string
-	Package        string
-	FlakinessScore float64 // e.g., failure rate
-	TotalRuns      int
-	Failures       int
+	Name            string
+	Package         string
+	FlakinessScore  float64 // composite score in [0,1]
+	TotalRuns       int
+	Failures        int
+	TransitionCount int
+	WilsonLow       float64
+	WilsonHigh      float64
+	Entropy         float64
+	EWMA            float64
+	IsMarkedFlaky   bool
+	IssueURL        string
 }
 
 // parseGoTestOutput parses the output from `go test -v` and returns a slice of TestResult.
@@ -55,7 +72,16 @@ func parseGoTestOutput(reader io.Reader) ([]TestResult, error) {
 	// We'll use the "ok/FAIL" lines to infer the package for the preceding individual tests.
 	testLineRegex := regexp.MustCompile(`^--- (PASS|FAIL|SKIP): (.+) \(([\d.]+)s\)$`)
 	packageLineRegex := regexp.MustCompile(`^(ok|FAIL|SKIP)\s+(\S+)\s+([\d.]+)s(?:\s+\[build failed\])?$`)
-
+	runLineRegex := regexp.MustCompile(`^=== RUN\s+(\S+)$`)
+
+	// output buffers lines logged by each currently-running test, keyed by
+	// test name, so a PASS/FAIL/SKIP line can check them for the flakymark
+	// sentinel. testStack tracks the nesting of "=== RUN" lines so that,
+	// once a subtest finishes, later output goes back to buffering under
+	// its still-running parent rather than the finished subtest's (deleted)
+	// buffer.
+	output := make(map[string][]string)
+	var testStack []string
 	currentPackage := ""
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -72,16 +98,29 @@ func parseGoTestOutput(reader io.Reader) ([]TestResult, error) {
 				continue
 			}
 
+			marked, issueURL := detectFlakyMark(output[testName])
 			results = append(results, TestResult{
-				Name:      testName,
-				Status:    status,
-				Duration:  duration,
-				Timestamp: now,
-				Package:   currentPackage, // Assign the last known package
+				Name:          testName,
+				Status:        status,
+				Duration:      duration,
+				Timestamp:     now,
+				Package:       currentPackage, // Assign the last known package
+				Output:        output[testName],
+				IsMarkedFlaky: marked,
+				IssueURL:      issueURL,
 			})
+			delete(output, testName)
+			if len(testStack) > 0 && testStack[len(testStack)-1] == testName {
+				testStack = testStack[:len(testStack)-1]
+			}
 		} else if matches := packageLineRegex.FindStringSubmatch(line); len(matches) >= 3 {
 			// This line indicates the status of a whole package
 			currentPackage = matches[2] // Update current package context
+		} else if matches := runLineRegex.FindStringSubmatch(line); len(matches) == 2 {
+			testStack = append(testStack, matches[1])
+		} else if len(testStack) > 0 {
+			currentTest := testStack[len(testStack)-1]
+			output[currentTest] = append(output[currentTest], line)
 		}
 	}
 
@@ -107,55 +146,72 @@ func parseGoTestOutput(reader io.Reader) ([]TestResult, error) {
 	return results, nil
 }
 
-// calculateFlakiness analyzes test results and identifies flaky tests.
-// For simplicity, a test is considered "flaky" if its failure rate exceeds a threshold.
-// In a real tool, this would involve more complex heuristics (e.g., pass-fail-pass patterns, retries).
+// calculateFlakiness analyzes test results and identifies flaky tests using
+// the scoring package's composite signal rather than a single failure-rate
+// threshold, so e.g. a test alternating pass/fail outranks one that fails
+// five times in a row then passes five times in a row at the same rate.
+// allResults is sorted by Timestamp per-test before scoring, since the
+// signals (transition count, EWMA) are order-sensitive.
 func calculateFlakiness(allResults []TestResult, threshold float64) []FlakyTest {
-	testAggregates := make(map[string]struct {
-		TotalRuns  int
-		Failures   int
-		LastResult TestResult
-	})
+	type aggregate struct {
+		Package string
+		Name    string
+		Results []TestResult
+	}
+	testAggregates := make(map[string]*aggregate)
 
 	for _, res := range allResults {
 		key := fmt.Sprintf("%s/%s", res.Package, res.Name) // Unique key for test
-		agg := testAggregates[key]
-		agg.TotalRuns++
-		if res.Status == "FAIL" {
-			agg.Failures++
+		agg, ok := testAggregates[key]
+		if !ok {
+			agg = &aggregate{Package: res.Package, Name: res.Name}
+			testAggregates[key] = agg
 		}
-		agg.LastResult = res // Keep track of the last result to get package name
-		testAggregates[key] = agg
+		agg.Results = append(agg.Results, res)
 	}
 
 	var flakyTests []FlakyTest
-	for key, agg := range testAggregates {
-		if agg.TotalRuns == 0 {
-			continue // Should not happen if test is in map
-		}
-		failureRate := float64(agg.Failures) / float64(agg.TotalRuns)
-
-		if failureRate > threshold && agg.Failures > 0 { // Must have at least one failure
-			// Extract package and name from the key or last result
-			parts := strings.SplitN(key, "/", 2)
-			pkg := ""
-			name := key
-			if len(parts) == 2 {
-				pkg = parts[0]
-				name = parts[1]
-			} else if agg.LastResult.Package != "" {
-				pkg = agg.LastResult.Package
-				name = agg.LastResult.Name
+	for _, agg := range testAggregates {
+		sort.Slice(agg.Results, func(i, j int) bool {
+			return agg.Results[i].Timestamp.Before(agg.Results[j].Timestamp)
+		})
+
+		failures := 0
+		marked, issueURL := false, ""
+		runs := make([]scoring.Run, len(agg.Results))
+		for i, res := range agg.Results {
+			failed := res.Status == "FAIL"
+			if failed {
+				failures++
+			}
+			if res.IsMarkedFlaky {
+				marked, issueURL = true, res.IssueURL
 			}
+			runs[i] = scoring.Run{Failed: failed}
+		}
+		if failures == 0 {
+			continue // Must have at least one failure to be considered flaky
+		}
 
-			flakyTests = append(flakyTests, FlakyTest{
-				Name:           name,
-				Package:        pkg,
-				FlakinessScore: failureRate,
-				TotalRuns:      agg.TotalRuns,
-				Failures:       agg.Failures,
-			})
+		signals := scoring.Score(runs)
+		if signals.Composite <= threshold {
+			continue
 		}
+
+		flakyTests = append(flakyTests, FlakyTest{
+			Name:            agg.Name,
+			Package:         agg.Package,
+			FlakinessScore:  signals.Composite,
+			TotalRuns:       len(agg.Results),
+			Failures:        failures,
+			TransitionCount: signals.TransitionCount,
+			WilsonLow:       signals.WilsonLow,
+			WilsonHigh:      signals.WilsonHigh,
+			Entropy:         signals.Entropy,
+			EWMA:            signals.EWMA,
+			IsMarkedFlaky:   marked,
+			IssueURL:        issueURL,
+		})
 	}
 
 	// Sort flaky tests by flakiness score in descending order
@@ -202,7 +258,7 @@ Or pipe directly:
 
 		var allTestResults []TestResult
 		for _, reader := range readers {
-			results, err := parseGoTestOutput(reader)
+			results, err := parseTestOutput(reader)
 			if err != nil {
 				log.Fatalf("Error parsing test output: %v", err)
 			}
@@ -219,56 +275,29 @@ Or pipe directly:
 
 		flakyTests := calculateFlakiness(allTestResults, flakinessThreshold)
 
-		fmt.Println("\n--- Flaky Test Report ---")
-		if len(flakyTests) == 0 {
-			fmt.Printf("No tests identified as flaky (threshold: %.0f%% failure rate).\n", flakinessThreshold*100)
-		} else {
-			fmt.Printf("Identified %d potentially flaky tests (threshold: %.0f%% failure rate):\n", len(flakyTests), flakinessThreshold*100)
-			fmt.Printf("%-50s %-20s %-15s %-10s %-10s\n", "TEST NAME", "PACKAGE", "FLAKINESS SCORE", "FAILURES", "TOTAL RUNS")
-			fmt.Println(strings.Repeat("-", 105))
-			for _, ft := range flakyTests {
-				fmt.Printf("%-50s %-20s %-15.2f%% %-10d %-10d\n",
-					ft.Name, ft.Package, ft.FlakinessScore*100, ft.Failures, ft.TotalRuns)
+		format, _ := cmd.Flags().GetString("format")
+		switch format {
+		case "text":
+			printFlakyTestsText(flakyTests, flakinessThreshold)
+			printTriage(triage(allTestResults, flakyTests))
+		case "json":
+			if err := printFlakyTestsJSON(flakyTests); err != nil {
+				log.Fatalf("Error printing JSON report: %v", err)
+			}
+		case "junit":
+			if err := printFlakyTestsJUnit(flakyTests); err != nil {
+				log.Fatalf("Error printing JUnit report: %v", err)
 			}
+		case "github":
+			printFlakyTestsGithub(flakyTests)
+		default:
+			log.Fatalf("Unknown --format %q: must be one of text, json, junit, github", format)
 		}
 	},
 }
 
-// rerunCmd represents the rerun command
-var rerunCmd = &cobra.Command{
-	Use:   "rerun <test-name> [flags]",
-	Short: "Rerun a specific Go test multiple times to confirm flakiness",
-	Long: `This command simulates rerunning a specific Go test a given number of times.
-In a real implementation, this would execute 'go test -run <test-name>'
-and capture its output for analysis.
-
-Example:
-  flaky-test-tracker rerun TestMyFlakyFunction -n 100 -p github.com/my/repo/pkg
-`,
-	Args: cobra.ExactArgs(1), // Requires exactly one argument: the test name
-	Run: func(cmd *cobra.Command, args []string) {
-		testName := args[0]
-		numRuns, _ := cmd.Flags().GetInt("num-runs")
-		packageName, _ := cmd.Flags().GetString("package")
-
-		fmt.Printf("Simulating rerunning test '%s' from package '%s' %d times...\n", testName, packageName, numRuns)
-		fmt.Println("This is a placeholder. In a full implementation, this would:")
-		fmt.Println("1. Build your Go project.")
-		fmt.Println("2. Execute 'go test -run ^" + testName + "$ " + packageName + "' repeatedly.")
-		fmt.Println("3. Capture and analyze the output of each run.")
-		fmt.Println("4. Report on the consistency of the test's outcome over these runs.")
-		fmt.Println("\nFor example, you could use 'os/exec' to run 'go test':")
-		fmt.Println("  cmd := exec.Command(\"go\", \"test\", \"-v\", \"-run\", \"^\"+testName+\"$\", packageName)")
-		fmt.Println("  output, err := cmd.CombinedOutput()")
-		fmt.Println("  // Process output and errors")
-		fmt.Println("\nConsider using a container (e.g., Docker) for isolated reruns for better reliability.")
-	},
-}
-
 func init() {
-	// Add flags to the rerun command
-	rerunCmd.Flags().IntP("num-runs", "n", 10, "Number of times to rerun the test")
-	rerunCmd.Flags().StringP("package", "p", "./...", "Go package containing the test (e.g., github.com/user/repo/pkg or ./...)")
+	analyzeCmd.Flags().String("format", "text", "Output format: text, json, junit, github")
 
 	// Add commands to the root command
 	rootCmd.AddCommand(analyzeCmd)
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// goTestEvent mirrors a single line of `go test -json` output, as documented
+// in `go help test` under "Test2JSON". Package-level events omit Test.
+type goTestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// parseGoTestJSON parses the line-delimited JSON event stream produced by
+// `go test -json`. Unlike parseGoTestOutput it needs no package back-fill
+// heuristics: every event already carries its Package and Test, subtests
+// show up as "Parent/Sub" in Test and are kept as-is, and "output" events are
+// buffered per test so the final PASS/FAIL/SKIP result can carry the test's
+// captured log output.
+func parseGoTestJSON(reader io.Reader) ([]TestResult, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var results []TestResult
+	output := make(map[string][]string)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("error decoding go test -json event: %w", err)
+		}
+
+		// Package-level events (no Test field) don't produce a TestResult.
+		if ev.Test == "" {
+			continue
+		}
+
+		key := ev.Package + "\x00" + ev.Test
+
+		switch ev.Action {
+		case "output":
+			output[key] = append(output[key], ev.Output)
+		case "pass", "fail", "skip":
+			status := map[string]string{"pass": "PASS", "fail": "FAIL", "skip": "SKIP"}[ev.Action]
+			marked, issueURL := detectFlakyMark(output[key])
+			results = append(results, TestResult{
+				Name:          ev.Test,
+				Status:        status,
+				Duration:      ev.Elapsed,
+				Timestamp:     ev.Time,
+				Package:       ev.Package,
+				Output:        output[key],
+				IsMarkedFlaky: marked,
+				IssueURL:      issueURL,
+			})
+			delete(output, key)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading input: %w", err)
+	}
+
+	return results, nil
+}
+
+// parseTestOutput auto-detects whether reader holds the `go test -json`
+// event stream or plain `go test -v` text and dispatches to the matching
+// parser, falling back to the text parser when detection is inconclusive.
+func parseTestOutput(reader io.Reader) ([]TestResult, error) {
+	br := bufio.NewReader(reader)
+
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("error reading input: %w", err)
+		}
+		if b[0] == '\n' || b[0] == '\r' {
+			if _, err := br.Discard(1); err != nil {
+				return nil, fmt.Errorf("error reading input: %w", err)
+			}
+			continue
+		}
+		if b[0] == '{' {
+			return parseGoTestJSON(br)
+		}
+		return parseGoTestOutput(br)
+	}
+}
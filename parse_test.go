@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGoTestJSON(t *testing.T) {
+	input := strings.Join([]string{
+		`{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"pkg","Test":"TestA"}`,
+		`{"Time":"2024-01-01T00:00:00Z","Action":"output","Package":"pkg","Test":"TestA","Output":"some log line\n"}`,
+		`{"Time":"2024-01-01T00:00:01Z","Action":"pass","Package":"pkg","Test":"TestA","Elapsed":1.5}`,
+		`{"Time":"2024-01-01T00:00:01Z","Action":"run","Package":"pkg","Test":"TestB"}`,
+		`{"Time":"2024-01-01T00:00:01Z","Action":"output","Package":"pkg","Test":"TestB","Output":"flakytest: this is a known flaky test: https://issue/1\n"}`,
+		`{"Time":"2024-01-01T00:00:02Z","Action":"fail","Package":"pkg","Test":"TestB","Elapsed":0.2}`,
+		`{"Time":"2024-01-01T00:00:02Z","Action":"run","Package":"pkg","Test":"TestC/sub"}`,
+		`{"Time":"2024-01-01T00:00:03Z","Action":"pass","Package":"pkg","Test":"TestC/sub","Elapsed":0.1}`,
+		`{"Time":"2024-01-01T00:00:03Z","Action":"pass","Package":"pkg","Test":"TestC","Elapsed":0.1}`,
+		`{"Time":"2024-01-01T00:00:03Z","Action":"pass","Package":"pkg"}`, // package-level summary, no Test
+	}, "\n")
+
+	results, err := parseGoTestJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseGoTestJSON returned error: %v", err)
+	}
+
+	byName := make(map[string]TestResult, len(results))
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4 (package-level event must be skipped): %+v", len(results), results)
+	}
+
+	a, ok := byName["TestA"]
+	if !ok {
+		t.Fatalf("missing TestA result")
+	}
+	if a.Status != "PASS" || a.Package != "pkg" || a.Duration != 1.5 {
+		t.Errorf("TestA = %+v, want PASS/pkg/1.5s", a)
+	}
+	if len(a.Output) != 1 || a.Output[0] != "some log line\n" {
+		t.Errorf("TestA.Output = %q, want captured log line", a.Output)
+	}
+	if a.IsMarkedFlaky {
+		t.Errorf("TestA.IsMarkedFlaky = true, want false")
+	}
+
+	b, ok := byName["TestB"]
+	if !ok {
+		t.Fatalf("missing TestB result")
+	}
+	if b.Status != "FAIL" {
+		t.Errorf("TestB.Status = %q, want FAIL", b.Status)
+	}
+	if !b.IsMarkedFlaky || b.IssueURL != "https://issue/1" {
+		t.Errorf("TestB marker = (%v, %q), want (true, https://issue/1)", b.IsMarkedFlaky, b.IssueURL)
+	}
+
+	sub, ok := byName["TestC/sub"]
+	if !ok {
+		t.Fatalf("missing TestC/sub result, subtest hierarchy not preserved")
+	}
+	if sub.Status != "PASS" {
+		t.Errorf("TestC/sub.Status = %q, want PASS", sub.Status)
+	}
+}
+
+func TestParseGoTestOutput(t *testing.T) {
+	input := strings.Join([]string{
+		"=== RUN   TestA",
+		"--- PASS: TestA (0.01s)",
+		"=== RUN   TestB",
+		"flakytest: this is a known flaky test: https://issue/2",
+		"--- FAIL: TestB (0.02s)",
+		"=== RUN   TestParent",
+		"=== RUN   TestParent/sub",
+		"--- PASS: TestParent/sub (0.01s)",
+		"some log line after the subtest finished, still TestParent's output",
+		"--- PASS: TestParent (0.03s)",
+		"FAIL    example.com/pkg    0.060s",
+	}, "\n")
+
+	results, err := parseGoTestOutput(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseGoTestOutput returned error: %v", err)
+	}
+
+	byName := make(map[string]TestResult, len(results))
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4: %+v", len(results), results)
+	}
+
+	a := byName["TestA"]
+	if a.Status != "PASS" || a.Duration != 0.01 {
+		t.Errorf("TestA = %+v, want PASS/0.01s", a)
+	}
+
+	b := byName["TestB"]
+	if b.Status != "FAIL" {
+		t.Errorf("TestB.Status = %q, want FAIL", b.Status)
+	}
+	if !b.IsMarkedFlaky || b.IssueURL != "https://issue/2" {
+		t.Errorf("TestB marker = (%v, %q), want (true, https://issue/2)", b.IsMarkedFlaky, b.IssueURL)
+	}
+
+	parent := byName["TestParent"]
+	if parent.Status != "PASS" {
+		t.Errorf("TestParent.Status = %q, want PASS", parent.Status)
+	}
+	if len(parent.Output) != 1 || parent.Output[0] != "some log line after the subtest finished, still TestParent's output" {
+		t.Errorf("TestParent.Output = %q, want the post-subtest log line attributed to the parent", parent.Output)
+	}
+
+	sub := byName["TestParent/sub"]
+	if sub.Status != "PASS" {
+		t.Errorf("TestParent/sub.Status = %q, want PASS", sub.Status)
+	}
+}
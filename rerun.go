@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// RerunAttempt pairs a TestResult with the attempt number it was observed
+// in, so a --json-out report can reconstruct the full per-attempt history.
+type RerunAttempt struct {
+	Attempt int        `json:"attempt"`
+	Result  TestResult `json:"result"`
+}
+
+// RerunReport is the machine-readable summary written by --json-out.
+type RerunReport struct {
+	Package      string         `json:"package"`
+	Test         string         `json:"test"`
+	MaxAttempts  int            `json:"max_attempts"`
+	Attempts     []RerunAttempt `json:"attempts"`
+	Flaky        []string       `json:"flaky"`
+	HardFailures []string       `json:"hard_failures"`
+}
+
+// isTargetedResult reports whether resultName is the exact test targeted by
+// a `go test -run` invocation for target, or one of its subtests. Go has no
+// way to run a subtest without also executing its parent test function, so
+// a retry of "Parent/Sub" collaterally re-executes "Parent" itself; that
+// collateral result must not be mistaken for an actual retry of "Parent".
+func isTargetedResult(resultName, target string) bool {
+	return resultName == target || strings.HasPrefix(resultName, target+"/")
+}
+
+// runRegexFor builds a `go test -run` pattern that pins an exact test name,
+// anchoring each "/"-separated subtest segment individually. This avoids
+// Go's -run regex matching unrelated tests that merely share a prefix.
+func runRegexFor(testName string) string {
+	parts := strings.Split(testName, "/")
+	segs := make([]string, len(parts))
+	for i, p := range parts {
+		segs[i] = "^" + regexp.QuoteMeta(p) + "$"
+	}
+	return strings.Join(segs, "/")
+}
+
+// runGoTest executes `go test -json` for the given package, restricted to
+// tests matching runRegex, and parses the resulting event stream.
+func runGoTest(pkg, runRegex, timeout string, parallel int) ([]TestResult, error) {
+	args := []string{"test", "-json", "-count=1", "-run", runRegex}
+	if timeout != "" {
+		args = append(args, "-timeout", timeout)
+	}
+	if parallel > 0 {
+		args = append(args, "-parallel", fmt.Sprintf("%d", parallel))
+	}
+	args = append(args, pkg)
+
+	cmd := exec.Command("go", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run() // non-nil on test failure; the JSON stream still reports it
+
+	results, err := parseGoTestJSON(&stdout)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing `go test -json` output for %s: %w (stderr: %s)", runRegex, err, stderr.String())
+	}
+	if len(results) == 0 && runErr != nil {
+		return nil, fmt.Errorf("go test failed to run for %s: %w (stderr: %s)", runRegex, runErr, stderr.String())
+	}
+	return results, nil
+}
+
+// rerunCmd represents the rerun command
+var rerunCmd = &cobra.Command{
+	Use:   "rerun <test-name> [flags]",
+	Short: "Rerun a Go test up to N times to detect flakiness",
+	Long: `Runs 'go test -json -run <test-name>' against a package, and on
+every attempt after the first, only re-runs the individual tests
+(including subtests) that failed in the previous attempt AND carry a
+flakymark.Mark sentinel in their output - one 'go test' invocation per
+subtest, since Go's -run regex can't cleanly target more than one at a
+time. An unmarked test gets a single attempt: if it fails, that's an
+unmarked flake that needs triage (mark it or fix it), not something
+rerun will silently retry.
+
+A test is reported flaky if it failed in at least one attempt and passed
+in a later one. A test that fails every attempt - or fails its only
+attempt without a marker - is a hard failure and causes rerun to exit
+non-zero.
+
+Example:
+  flaky-test-tracker rerun TestMyFlakyFunction -p ./pkg/foo --max-attempts 5
+
+Pass --db to additionally record every attempt's result into the same
+history store 'ingest' and 'report' use, so a rerun session feeds the
+same longitudinal flakiness queries as CI-collected history instead of
+being stranded in --json-out alone.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		testName := args[0]
+		pkg, _ := cmd.Flags().GetString("package")
+		maxAttempts, _ := cmd.Flags().GetInt("max-attempts")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		timeout, _ := cmd.Flags().GetString("timeout")
+		jsonOut, _ := cmd.Flags().GetString("json-out")
+		dbPath, _ := cmd.Flags().GetString("db")
+
+		var store *SQLiteStore
+		var meta RunMetadata
+		if dbPath != "" {
+			store = openHistoryStore(cmd)
+			defer store.Close()
+			meta = detectRunMetadata()
+		}
+		ctx := context.Background()
+		record := func(r TestResult) {
+			if store == nil {
+				return
+			}
+			if err := store.Record(ctx, r, meta); err != nil {
+				log.Fatalf("Error recording rerun result for %s: %v", r.Name, err)
+			}
+		}
+
+		var allAttempts []RerunAttempt
+		// history[test] is the ordered list of statuses observed for that
+		// exact test name (subtests get their own entry).
+		history := make(map[string][]string)
+
+		// marked tracks which test names carried the flakymark sentinel on
+		// some attempt; only those are retried on failure (see below).
+		marked := make(map[string]bool)
+
+		toRun := []string{testName}
+		for attempt := 1; attempt <= maxAttempts && len(toRun) > 0; attempt++ {
+			var failedThisAttempt []string
+
+			if attempt == 1 {
+				// First attempt: one invocation covers the test and all its
+				// subtests, since an unqualified -run pattern matches all
+				// descendants.
+				results, err := runGoTest(pkg, runRegexFor(testName), timeout, parallel)
+				if err != nil {
+					log.Fatalf("Error running test %q: %v", testName, err)
+				}
+				for _, r := range results {
+					history[r.Name] = append(history[r.Name], r.Status)
+					allAttempts = append(allAttempts, RerunAttempt{Attempt: attempt, Result: r})
+					record(r)
+					if r.IsMarkedFlaky {
+						marked[r.Name] = true
+					}
+					if r.Status == "FAIL" && r.IsMarkedFlaky {
+						failedThisAttempt = append(failedThisAttempt, r.Name)
+					}
+				}
+			} else {
+				// Subsequent attempts: only re-run what failed last time,
+				// one `go test` invocation per (sub)test.
+				for _, name := range toRun {
+					results, err := runGoTest(pkg, runRegexFor(name), timeout, parallel)
+					if err != nil {
+						log.Fatalf("Error rerunning test %q: %v", name, err)
+					}
+					for _, r := range results {
+						if !isTargetedResult(r.Name, name) {
+							// Collateral result from the parent test function
+							// running alongside the subtest we actually
+							// retried - not itself a retry, so it must not
+							// feed history or the marked set.
+							continue
+						}
+						history[r.Name] = append(history[r.Name], r.Status)
+						allAttempts = append(allAttempts, RerunAttempt{Attempt: attempt, Result: r})
+						record(r)
+						if r.IsMarkedFlaky {
+							marked[r.Name] = true
+						}
+						if r.Status == "FAIL" && r.IsMarkedFlaky {
+							failedThisAttempt = append(failedThisAttempt, r.Name)
+						}
+					}
+				}
+			}
+
+			toRun = failedThisAttempt
+		}
+
+		var flaky, hardFailures []string
+		for name, statuses := range history {
+			failed, passed := false, false
+			for _, s := range statuses {
+				if s == "FAIL" {
+					failed = true
+				} else if s == "PASS" {
+					passed = true
+				}
+			}
+			switch {
+			case failed && passed:
+				flaky = append(flaky, name)
+			case failed && !passed:
+				hardFailures = append(hardFailures, name)
+			}
+		}
+
+		fmt.Println("\n--- Rerun Report ---")
+		fmt.Printf("Test: %s (package: %s, max attempts: %d)\n", testName, pkg, maxAttempts)
+		if len(flaky) == 0 && len(hardFailures) == 0 {
+			fmt.Println("No flakiness detected: all observed tests passed consistently.")
+		}
+		for _, name := range flaky {
+			if marked[name] {
+				fmt.Printf("FLAKY (marked):   %s %v\n", name, history[name])
+			} else {
+				fmt.Printf("FLAKY (unmarked): %s %v\n", name, history[name])
+			}
+		}
+		for _, name := range hardFailures {
+			if marked[name] {
+				fmt.Printf("HARD FAILURE (marked, exhausted attempts):   %s %v\n", name, history[name])
+			} else {
+				fmt.Printf("HARD FAILURE (unmarked, needs triage):       %s %v\n", name, history[name])
+			}
+		}
+
+		if jsonOut != "" {
+			report := RerunReport{
+				Package:      pkg,
+				Test:         testName,
+				MaxAttempts:  maxAttempts,
+				Attempts:     allAttempts,
+				Flaky:        flaky,
+				HardFailures: hardFailures,
+			}
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				log.Fatalf("Error marshaling rerun report: %v", err)
+			}
+			if err := os.WriteFile(jsonOut, data, 0o644); err != nil {
+				log.Fatalf("Error writing rerun report to %s: %v", jsonOut, err)
+			}
+		}
+
+		if len(hardFailures) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rerunCmd.Flags().StringP("package", "p", "./...", "Go package containing the test (e.g., github.com/user/repo/pkg or ./...)")
+	rerunCmd.Flags().Int("max-attempts", 3, "Maximum number of attempts before giving up on a failing test")
+	rerunCmd.Flags().Int("parallel", 0, "Value to pass to 'go test -parallel' (0 leaves the default)")
+	rerunCmd.Flags().String("timeout", "30s", "Value to pass to 'go test -timeout'")
+	rerunCmd.Flags().String("json-out", "", "Write a machine-readable per-attempt report to this file")
+	rerunCmd.Flags().String("db", "", "Also record every attempt's result into the SQLite history store at this path, so rerun output feeds 'report' like ingested CI history does (empty disables recording)")
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestIsTargetedResult(t *testing.T) {
+	tests := []struct {
+		resultName string
+		target     string
+		want       bool
+	}{
+		{"TestParent", "TestParent", true},
+		{"TestParent/sub", "TestParent/sub", true},
+		{"TestParent/sub", "TestParent", true},
+		{"TestParent", "TestParent/sub", false},
+		{"TestParentOther", "TestParent", false},
+		{"TestOther", "TestParent", false},
+	}
+	for _, tt := range tests {
+		if got := isTargetedResult(tt.resultName, tt.target); got != tt.want {
+			t.Errorf("isTargetedResult(%q, %q) = %v, want %v", tt.resultName, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestRunRegexFor(t *testing.T) {
+	tests := []struct {
+		testName string
+		want     string
+	}{
+		{"TestA", "^TestA$"},
+		{"TestParent/sub", "^TestParent$/^sub$"},
+		{"TestA.B/c.d", `^TestA\.B$/^c\.d$`},
+	}
+	for _, tt := range tests {
+		if got := runRegexFor(tt.testName); got != tt.want {
+			t.Errorf("runRegexFor(%q) = %q, want %q", tt.testName, got, tt.want)
+		}
+	}
+}
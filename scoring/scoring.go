@@ -0,0 +1,150 @@
+// Package scoring computes flakiness signals from a test's chronological
+// pass/fail history. It deliberately stays independent of go test parsing
+// or storage so new signals can be added and tuned without touching the
+// rest of the tool.
+package scoring
+
+import "math"
+
+// z95 is the z-score for a 95% confidence Wilson score interval.
+const z95 = 1.96
+
+// ewmaWindow caps how many of the most recent runs feed the EWMA signal, so
+// long-flaky tests that were recently fixed decay out of the score.
+const ewmaWindow = 20
+
+// ewmaAlpha is the EWMA smoothing factor: higher weights recent runs more.
+const ewmaAlpha = 0.3
+
+// Run is one chronological observation of a test's pass/fail outcome.
+type Run struct {
+	Failed bool
+}
+
+// Signals holds the individual flakiness signals computed for a single
+// test, plus the Composite score that combines them.
+type Signals struct {
+	TransitionCount int     // PASS<->FAIL transitions, in chronological order
+	WilsonLow       float64 // Wilson score lower bound on failure probability
+	WilsonHigh      float64 // Wilson score upper bound on failure probability
+	Entropy         float64 // -p*log2(p) - (1-p)*log2(1-p), weighted by run count
+	EWMA            float64 // exponentially weighted moving average of failure, most recent runs only
+	Composite       float64 // combined flakiness score in [0,1]
+}
+
+// Score computes Signals from runs, which callers MUST already have sorted
+// in chronological order (oldest first) - Score does not re-sort, since
+// callers may be able to do so once across several signals more cheaply.
+func Score(runs []Run) Signals {
+	n := len(runs)
+	if n == 0 {
+		return Signals{}
+	}
+
+	failures := 0
+	for _, r := range runs {
+		if r.Failed {
+			failures++
+		}
+	}
+	p := float64(failures) / float64(n)
+
+	s := Signals{
+		TransitionCount: transitions(runs),
+		Entropy:         weightedEntropy(p, n),
+		EWMA:            ewma(runs),
+	}
+	s.WilsonLow, s.WilsonHigh = wilsonInterval(p, n)
+	s.Composite = composite(s, n)
+
+	return s
+}
+
+// transitions counts PASS<->FAIL flips in chronological order. A test that
+// alternates every run scores higher here than one that fails N times in a
+// row then passes N times in a row, even at an identical failure rate.
+func transitions(runs []Run) int {
+	count := 0
+	for i := 1; i < len(runs); i++ {
+		if runs[i].Failed != runs[i-1].Failed {
+			count++
+		}
+	}
+	return count
+}
+
+// wilsonInterval returns the Wilson score interval bounds for failure
+// probability p observed over n trials. This avoids over-flagging tests
+// with only a handful of runs, where a naive failure rate is noisy.
+func wilsonInterval(p float64, n int) (low, high float64) {
+	if n == 0 {
+		return 0, 0
+	}
+	nf := float64(n)
+	denom := 1 + z95*z95/nf
+	center := p + z95*z95/(2*nf)
+	margin := z95 * math.Sqrt(p*(1-p)/nf+z95*z95/(4*nf*nf))
+	low = (center - margin) / denom
+	high = (center + margin) / denom
+	return clamp01(low), clamp01(high)
+}
+
+// weightedEntropy returns the binary entropy of failure probability p,
+// damped toward zero for small sample sizes so a single failure in one run
+// doesn't score as "maximally flaky".
+func weightedEntropy(p float64, n int) float64 {
+	if p <= 0 || p >= 1 {
+		return 0
+	}
+	h := -(p*math.Log2(p) + (1-p)*math.Log2(1-p))
+	weight := float64(n) / float64(n+1)
+	return h * weight
+}
+
+// ewma computes an exponentially weighted moving average of failure over
+// the most recent ewmaWindow runs, so flakes that were recently fixed decay
+// out of the score instead of being weighted equally with old failures.
+func ewma(runs []Run) float64 {
+	start := 0
+	if len(runs) > ewmaWindow {
+		start = len(runs) - ewmaWindow
+	}
+	window := runs[start:]
+
+	avg := 0.0
+	for _, r := range window {
+		v := 0.0
+		if r.Failed {
+			v = 1.0
+		}
+		avg = ewmaAlpha*v + (1-ewmaAlpha)*avg
+	}
+	return avg
+}
+
+// composite combines the individual signals into a single score in [0,1].
+// Flakiness means alternating between pass and fail, so TransitionCount == 0
+// gates the whole score to 0: a test that fails every run (or passes every
+// run) is consistently broken (or healthy), not flaky, no matter how high
+// its raw failure rate, entropy, or EWMA read in isolation - those signals
+// can't tell "broken" from "flaky" apart on their own, only the transition
+// count can. Once that gate passes, transition rate is weighted most
+// heavily, with the Wilson lower bound, entropy, and EWMA folded in so a
+// test that alternates more often, or has recently relapsed, scores higher.
+func composite(s Signals, n int) float64 {
+	if s.TransitionCount == 0 {
+		return 0
+	}
+	transitionRatio := float64(s.TransitionCount) / float64(n-1)
+	return clamp01((2*transitionRatio + s.WilsonLow + s.Entropy + s.EWMA) / 5)
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package scoring
+
+import "testing"
+
+func runsOf(statuses ...bool) []Run {
+	runs := make([]Run, len(statuses))
+	for i, failed := range statuses {
+		runs[i] = Run{Failed: failed}
+	}
+	return runs
+}
+
+func TestScore(t *testing.T) {
+	const (
+		fail = true
+		pass = false
+	)
+
+	tests := []struct {
+		name                string
+		runs                []Run
+		wantTransitionCount int
+		wantCompositeZero   bool // composite must gate to exactly 0
+		wantCompositePos    bool // composite must be > 0
+	}{
+		{
+			name:                "no runs",
+			runs:                nil,
+			wantTransitionCount: 0,
+			wantCompositeZero:   true,
+		},
+		{
+			name:                "single failing run is not flaky",
+			runs:                runsOf(fail),
+			wantTransitionCount: 0,
+			wantCompositeZero:   true,
+		},
+		{
+			name:                "consistently failing is not flaky",
+			runs:                runsOf(fail, fail, fail, fail, fail),
+			wantTransitionCount: 0,
+			wantCompositeZero:   true,
+		},
+		{
+			name:                "consistently passing is not flaky",
+			runs:                runsOf(pass, pass, pass, pass),
+			wantTransitionCount: 0,
+			wantCompositeZero:   true,
+		},
+		{
+			name:                "alternating pass/fail is flaky",
+			runs:                runsOf(pass, fail, pass, fail),
+			wantTransitionCount: 3,
+			wantCompositePos:    true,
+		},
+		{
+			name:                "fails then recovers is flaky",
+			runs:                runsOf(fail, fail, pass, pass, pass),
+			wantTransitionCount: 1,
+			wantCompositePos:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(tt.runs)
+			if got.TransitionCount != tt.wantTransitionCount {
+				t.Errorf("TransitionCount = %d, want %d", got.TransitionCount, tt.wantTransitionCount)
+			}
+			if tt.wantCompositeZero && got.Composite != 0 {
+				t.Errorf("Composite = %v, want exactly 0", got.Composite)
+			}
+			if tt.wantCompositePos && got.Composite <= 0 {
+				t.Errorf("Composite = %v, want > 0", got.Composite)
+			}
+			if got.Composite < 0 || got.Composite > 1 {
+				t.Errorf("Composite = %v, want in [0,1]", got.Composite)
+			}
+		})
+	}
+}
+
+// TestScoreConsistentFailureRanksBelowIntermittent guards the specific
+// regression a pure failure-rate composite invites: a test that fails every
+// run must not outrank one that actually alternates between pass and fail.
+func TestScoreConsistentFailureRanksBelowIntermittent(t *testing.T) {
+	consistentlyFailing := Score(runsOf(true, true, true, true, true))
+	intermittent := Score(runsOf(false, true, false, true, false))
+
+	if consistentlyFailing.Composite != 0 {
+		t.Errorf("consistently failing Composite = %v, want exactly 0", consistentlyFailing.Composite)
+	}
+	if intermittent.Composite <= consistentlyFailing.Composite {
+		t.Errorf("intermittent Composite = %v, want > consistently failing Composite = %v",
+			intermittent.Composite, consistentlyFailing.Composite)
+	}
+}
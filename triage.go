@@ -0,0 +1,91 @@
+package main
+
+import "fmt"
+
+// TestSummary is a lightweight per-test aggregate, used where a full
+// FlakyTest (with flakiness signals) isn't needed.
+type TestSummary struct {
+	Name      string
+	Package   string
+	TotalRuns int
+	IssueURL  string
+}
+
+// TriageBuckets splits a run's tests into the three buckets the flakymark
+// workflow cares about.
+type TriageBuckets struct {
+	MarkedFlaky   []FlakyTest   // marked and currently flaky: expected, no action needed
+	UnmarkedFlaky []FlakyTest   // flaky but nobody marked it: needs triage
+	MarkedPassing []TestSummary // marked but consistently passing: candidate for un-marking
+}
+
+// triage buckets allResults (and the flakyTests already computed from them)
+// by flakymark status.
+func triage(allResults []TestResult, flakyTests []FlakyTest) TriageBuckets {
+	type aggregate struct {
+		Package   string
+		Name      string
+		IssueURL  string
+		TotalRuns int
+		Failures  int
+		Marked    bool
+	}
+	aggregates := make(map[string]*aggregate)
+
+	for _, res := range allResults {
+		key := fmt.Sprintf("%s/%s", res.Package, res.Name)
+		agg, ok := aggregates[key]
+		if !ok {
+			agg = &aggregate{Package: res.Package, Name: res.Name}
+			aggregates[key] = agg
+		}
+		agg.TotalRuns++
+		if res.Status == "FAIL" {
+			agg.Failures++
+		}
+		if res.IsMarkedFlaky {
+			agg.Marked, agg.IssueURL = true, res.IssueURL
+		}
+	}
+
+	flakyByKey := make(map[string]FlakyTest, len(flakyTests))
+	for _, ft := range flakyTests {
+		flakyByKey[fmt.Sprintf("%s/%s", ft.Package, ft.Name)] = ft
+	}
+
+	var buckets TriageBuckets
+	for key, agg := range aggregates {
+		ft, isFlaky := flakyByKey[key]
+		switch {
+		case agg.Marked && isFlaky:
+			buckets.MarkedFlaky = append(buckets.MarkedFlaky, ft)
+		case !agg.Marked && isFlaky:
+			buckets.UnmarkedFlaky = append(buckets.UnmarkedFlaky, ft)
+		case agg.Marked && agg.Failures == 0:
+			buckets.MarkedPassing = append(buckets.MarkedPassing, TestSummary{
+				Name:      agg.Name,
+				Package:   agg.Package,
+				TotalRuns: agg.TotalRuns,
+				IssueURL:  agg.IssueURL,
+			})
+		}
+	}
+	return buckets
+}
+
+// printTriage renders the three flakymark buckets to stdout.
+func printTriage(buckets TriageBuckets) {
+	fmt.Println("\n--- Flakymark Triage ---")
+	fmt.Printf("Marked & flaky (expected): %d\n", len(buckets.MarkedFlaky))
+	for _, ft := range buckets.MarkedFlaky {
+		fmt.Printf("  %s/%s (%.2f%% flakiness score) - %s\n", ft.Package, ft.Name, ft.FlakinessScore*100, ft.IssueURL)
+	}
+	fmt.Printf("Unmarked & flaky (needs triage): %d\n", len(buckets.UnmarkedFlaky))
+	for _, ft := range buckets.UnmarkedFlaky {
+		fmt.Printf("  %s/%s (%.2f%% flakiness score)\n", ft.Package, ft.Name, ft.FlakinessScore*100)
+	}
+	fmt.Printf("Marked but consistently passing (candidate for un-marking): %d\n", len(buckets.MarkedPassing))
+	for _, ts := range buckets.MarkedPassing {
+		fmt.Printf("  %s/%s (%d runs) - %s\n", ts.Package, ts.Name, ts.TotalRuns, ts.IssueURL)
+	}
+}